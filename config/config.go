@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"go.uber.org/multierr"
+)
+
+// Config is the fully resolved application configuration: defaults,
+// overridden by the config file, overridden by the environment.
+type Config struct {
+	Listen string
+	Redis  RedisConfig
+	Permit PermitConfig
+	Kafka  KafkaConfig
+	Log    LogConfig
+	WS     WSConfig
+	// Permissions optionally seeds the permission cache at startup, so
+	// known grants don't all miss on the first request after a
+	// restart. Keys are full Redis keys as built by PermissionKey
+	// (e.g. "perm:alice:cup1"), values are the allowed actions.
+	Permissions map[string][]string
+}
+
+// RedisConfig configures the Redis client built by RedisInit.
+type RedisConfig struct {
+	Addr        string
+	Password    string
+	DB          int
+	DialTimeout time.Duration
+}
+
+// PermitConfig configures the Permit.io client built by PermitInit.
+type PermitConfig struct {
+	APIKey string
+	PDPUrl string
+}
+
+// KafkaConfig configures the event producer built by events.Init.
+type KafkaConfig struct {
+	Brokers []string
+	Topic   string
+}
+
+// LogConfig configures the application's log verbosity.
+type LogConfig struct {
+	Level string
+}
+
+// WSConfig configures the WebSocket game-session endpoint. An empty
+// AllowedOrigins allows any origin, which is fine for local dev but
+// should be set explicitly in production.
+type WSConfig struct {
+	AllowedOrigins []string
+}
+
+const (
+	defaultListen      = ":1224"
+	defaultRedisAddr   = "127.0.0.1:6379"
+	defaultDialTimeout = 20 * time.Second
+	defaultPDPUrl      = "https://cloudpdp.api.permit.io"
+	defaultLogLevel    = "info"
+)
+
+// fileConfig mirrors Config's shape for decoding beerpong.toml; it
+// uses strings for anything that isn't a TOML primitive (durations)
+// so the zero value always means "not set in the file".
+type fileConfig struct {
+	Listen string `toml:"listen"`
+	Redis  struct {
+		Addr        string `toml:"addr"`
+		Password    string `toml:"password"`
+		DB          int    `toml:"db"`
+		DialTimeout string `toml:"dial_timeout"`
+	} `toml:"redis"`
+	Permit struct {
+		APIKey string `toml:"api_key"`
+		PDPUrl string `toml:"pdp_url"`
+	} `toml:"permit"`
+	Kafka struct {
+		Brokers []string `toml:"brokers"`
+		Topic   string   `toml:"topic"`
+	} `toml:"kafka"`
+	Log struct {
+		Level string `toml:"level"`
+	} `toml:"log"`
+	WS struct {
+		AllowedOrigins []string `toml:"allowed_origins"`
+	} `toml:"ws"`
+	Permissions map[string][]string `toml:"permissions"`
+}
+
+// Load builds a Config from defaults, the TOML file at path (if it
+// exists), and environment overrides, in that order. A missing file
+// is not an error; a missing Permit API key is, and is returned
+// alongside any other required-value errors instead of killing the
+// process outright.
+func Load(path string) (*Config, error) {
+	cfg := &Config{
+		Listen: defaultListen,
+		Redis: RedisConfig{
+			Addr:        defaultRedisAddr,
+			DialTimeout: defaultDialTimeout,
+		},
+		Permit: PermitConfig{
+			PDPUrl: defaultPDPUrl,
+		},
+		Log: LogConfig{Level: defaultLogLevel},
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		var file fileConfig
+		if _, err := toml.DecodeFile(path, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		mergeFile(cfg, &file)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	applyEnvOverrides(cfg)
+
+	var errs error
+	if apiKey, err := CheckEnv("API_KEY"); err == nil {
+		cfg.Permit.APIKey = apiKey
+	} else if cfg.Permit.APIKey == "" {
+		errs = multierr.Append(errs, err)
+	}
+
+	return cfg, errs
+}
+
+func mergeFile(cfg *Config, file *fileConfig) {
+	if file.Listen != "" {
+		cfg.Listen = file.Listen
+	}
+	if file.Redis.Addr != "" {
+		cfg.Redis.Addr = file.Redis.Addr
+	}
+	if file.Redis.Password != "" {
+		cfg.Redis.Password = file.Redis.Password
+	}
+	if file.Redis.DB != 0 {
+		cfg.Redis.DB = file.Redis.DB
+	}
+	if file.Redis.DialTimeout != "" {
+		if d, err := time.ParseDuration(file.Redis.DialTimeout); err == nil {
+			cfg.Redis.DialTimeout = d
+		}
+	}
+	if file.Permit.APIKey != "" {
+		cfg.Permit.APIKey = file.Permit.APIKey
+	}
+	if file.Permit.PDPUrl != "" {
+		cfg.Permit.PDPUrl = file.Permit.PDPUrl
+	}
+	if len(file.Kafka.Brokers) > 0 {
+		cfg.Kafka.Brokers = file.Kafka.Brokers
+	}
+	if file.Kafka.Topic != "" {
+		cfg.Kafka.Topic = file.Kafka.Topic
+	}
+	if file.Log.Level != "" {
+		cfg.Log.Level = file.Log.Level
+	}
+	if len(file.WS.AllowedOrigins) > 0 {
+		cfg.WS.AllowedOrigins = file.WS.AllowedOrigins
+	}
+	if len(file.Permissions) > 0 {
+		cfg.Permissions = file.Permissions
+	}
+}
+
+func applyEnvOverrides(cfg *Config) {
+	if v, ok := os.LookupEnv("LISTEN_ADDR"); ok {
+		cfg.Listen = v
+	}
+	if v, ok := os.LookupEnv("REDIS_ADDR"); ok {
+		cfg.Redis.Addr = v
+	}
+	if v, ok := os.LookupEnv("REDIS_PASSWORD"); ok {
+		cfg.Redis.Password = v
+	}
+	if v, ok := os.LookupEnv("REDIS_DB"); ok {
+		if db, err := strconv.Atoi(v); err == nil {
+			cfg.Redis.DB = db
+		}
+	}
+	if v, ok := os.LookupEnv("REDIS_DIAL_TIMEOUT"); ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Redis.DialTimeout = d
+		}
+	}
+	if v, ok := os.LookupEnv("PERMIT_PDP_URL"); ok {
+		cfg.Permit.PDPUrl = v
+	}
+	if v, ok := os.LookupEnv("KAFKA_BROKERS"); ok {
+		cfg.Kafka.Brokers = strings.Split(v, ",")
+	}
+	if v, ok := os.LookupEnv("KAFKA_TOPIC"); ok {
+		cfg.Kafka.Topic = v
+	}
+	if v, ok := os.LookupEnv("LOG_LEVEL"); ok {
+		cfg.Log.Level = v
+	}
+	if v, ok := os.LookupEnv("WS_ALLOWED_ORIGINS"); ok {
+		cfg.WS.AllowedOrigins = strings.Split(v, ",")
+	}
+}
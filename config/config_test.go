@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "beerpong.toml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadDefaultsWithNoFile(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.Listen != defaultListen {
+		t.Errorf("Listen = %q, want default %q", cfg.Listen, defaultListen)
+	}
+	if cfg.Redis.Addr != defaultRedisAddr {
+		t.Errorf("Redis.Addr = %q, want default %q", cfg.Redis.Addr, defaultRedisAddr)
+	}
+	if cfg.Permit.PDPUrl != defaultPDPUrl {
+		t.Errorf("Permit.PDPUrl = %q, want default %q", cfg.Permit.PDPUrl, defaultPDPUrl)
+	}
+	if cfg.Permit.APIKey != "test-key" {
+		t.Errorf("Permit.APIKey = %q, want %q", cfg.Permit.APIKey, "test-key")
+	}
+}
+
+func TestLoadFileOverridesDefaults(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	path := writeConfigFile(t, `
+listen = ":9000"
+
+[redis]
+addr = "redis.internal:6380"
+db = 2
+dial_timeout = "5s"
+
+[permit]
+pdp_url = "https://pdp.internal"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.Listen != ":9000" {
+		t.Errorf("Listen = %q, want %q", cfg.Listen, ":9000")
+	}
+	if cfg.Redis.Addr != "redis.internal:6380" {
+		t.Errorf("Redis.Addr = %q, want %q", cfg.Redis.Addr, "redis.internal:6380")
+	}
+	if cfg.Redis.DB != 2 {
+		t.Errorf("Redis.DB = %d, want 2", cfg.Redis.DB)
+	}
+	if cfg.Redis.DialTimeout != 5*time.Second {
+		t.Errorf("Redis.DialTimeout = %v, want 5s", cfg.Redis.DialTimeout)
+	}
+	if cfg.Permit.PDPUrl != "https://pdp.internal" {
+		t.Errorf("Permit.PDPUrl = %q, want %q", cfg.Permit.PDPUrl, "https://pdp.internal")
+	}
+}
+
+func TestLoadEnvOverridesFile(t *testing.T) {
+	path := writeConfigFile(t, `
+listen = ":9000"
+
+[redis]
+addr = "redis.internal:6380"
+`)
+
+	t.Setenv("API_KEY", "env-key")
+	t.Setenv("LISTEN_ADDR", ":9999")
+	t.Setenv("REDIS_ADDR", "redis.env:6379")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.Listen != ":9999" {
+		t.Errorf("Listen = %q, want env override %q", cfg.Listen, ":9999")
+	}
+	if cfg.Redis.Addr != "redis.env:6379" {
+		t.Errorf("Redis.Addr = %q, want env override %q", cfg.Redis.Addr, "redis.env:6379")
+	}
+	if cfg.Permit.APIKey != "env-key" {
+		t.Errorf("Permit.APIKey = %q, want %q", cfg.Permit.APIKey, "env-key")
+	}
+}
+
+func TestLoadMissingAPIKeyReturnsErrorInsteadOfFatal(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.toml"))
+	if err == nil {
+		t.Fatal("expected an error when API_KEY is unset, got nil")
+	}
+}
+
+func TestLoadPermissionsSeedFromFile(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	path := writeConfigFile(t, `
+[permissions]
+"perm:alice:cup1" = ["beer", "pour"]
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	actions := cfg.Permissions["perm:alice:cup1"]
+	if len(actions) != 2 || actions[0] != "beer" || actions[1] != "pour" {
+		t.Errorf("Permissions[\"perm:alice:cup1\"] = %v, want [beer pour]", actions)
+	}
+}
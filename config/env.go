@@ -1,14 +1,17 @@
 package config
 
 import (
-	"log"
+	"fmt"
 	"os"
 )
 
-func CheckEnv(key string) string {
+// CheckEnv reads key from the environment, returning an error instead
+// of killing the process so callers like Load can aggregate every
+// missing variable into a single reported failure.
+func CheckEnv(key string) (string, error) {
 	value := os.Getenv(key)
 	if value == "" {
-		log.Fatalf("WARNING!: Environment variable %s is missing", key)
+		return "", fmt.Errorf("environment variable %s is missing", key)
 	}
-	return value
+	return value, nil
 }
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Event is a single keyspace notification forwarded to a Watch
+// subscriber.
+type Event struct {
+	Key       string
+	Operation string
+	Timestamp time.Time
+}
+
+// Watcher fans out Redis keyspace notifications to per-key subscriber
+// channels, so cupHandler and future WebSocket endpoints can push
+// real-time updates instead of polling Redis.
+type Watcher struct {
+	mu          sync.RWMutex
+	subscribers map[string][]chan Event
+}
+
+// KeyWatcher is the package-level Watcher, initialized by
+// KeyWatcherInit alongside RedisClient.
+var KeyWatcher *Watcher
+
+// KeyWatcherInit enables Redis keyspace notifications and starts
+// listening for changes to ball:* and cup:* keys. It must be called
+// after RedisInit.
+func KeyWatcherInit() {
+	if RedisClient == nil {
+		log.Println("❌ KeyWatcher: Redis client is not initialized")
+		return
+	}
+
+	ctx := context.Background()
+	if err := RedisClient.ConfigSet(ctx, "notify-keyspace-events", "KEA").Err(); err != nil {
+		log.Printf("⚠️ KeyWatcher: server rejected notify-keyspace-events, keyspace notifications may be disabled: %v", err)
+	}
+
+	KeyWatcher = &Watcher{subscribers: make(map[string][]chan Event)}
+	go KeyWatcher.listen(ctx)
+
+	log.Println("✅ KeyWatcher listening for ball:* and cup:* changes")
+}
+
+// Watch returns a channel that receives every Event for key. The
+// channel is buffered; a slow or abandoned subscriber has events
+// dropped for it rather than blocking the dispatcher. Callers must
+// call Unwatch with the same channel once they stop reading it, or
+// the subscriber entry leaks for the life of the process.
+func (w *Watcher) Watch(key string) <-chan Event {
+	ch := make(chan Event, 16)
+
+	w.mu.Lock()
+	w.subscribers[key] = append(w.subscribers[key], ch)
+	w.mu.Unlock()
+
+	return ch
+}
+
+// Unwatch removes ch from key's subscriber list and closes it. It is
+// a no-op if ch was already removed.
+func (w *Watcher) Unwatch(key string, ch <-chan Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	subs := w.subscribers[key]
+	for i, sub := range subs {
+		if sub == ch {
+			w.subscribers[key] = append(subs[:i], subs[i+1:]...)
+			close(sub)
+			break
+		}
+	}
+
+	if len(w.subscribers[key]) == 0 {
+		delete(w.subscribers, key)
+	}
+}
+
+// listen subscribes to keyspace notifications and reconnects with
+// exponential backoff if the pub/sub connection drops.
+func (w *Watcher) listen(ctx context.Context) {
+	backoff := time.Second
+
+	// RedisClient may be configured against a non-zero logical DB
+	// (REDIS_DB / config file db); keyspace notifications are scoped
+	// per-DB, so the subscribe patterns must match it.
+	db := RedisClient.Options().DB
+	ballPattern := fmt.Sprintf("__keyspace@%d__:ball:*", db)
+	cupPattern := fmt.Sprintf("__keyspace@%d__:cup:*", db)
+	prefix := fmt.Sprintf("__keyspace@%d__:", db)
+
+	for {
+		pubsub := RedisClient.PSubscribe(ctx, ballPattern, cupPattern)
+		if _, err := pubsub.Receive(ctx); err != nil {
+			log.Printf("❌ KeyWatcher: subscribe failed, retrying in %s: %v", backoff, err)
+			pubsub.Close()
+			time.Sleep(backoff)
+			backoff = nextBackoff(backoff)
+			continue
+		}
+
+		backoff = time.Second
+		for msg := range pubsub.Channel() {
+			w.dispatch(msg, prefix)
+		}
+
+		log.Printf("⚠️ KeyWatcher: pub/sub connection dropped, reconnecting in %s", backoff)
+		pubsub.Close()
+		time.Sleep(backoff)
+		backoff = nextBackoff(backoff)
+	}
+}
+
+func (w *Watcher) dispatch(msg *redis.Message, prefix string) {
+	// msg.Channel is "<prefix><key>" (e.g. "__keyspace@0__:ball:u1");
+	// msg.Payload is the op name (e.g. "set", "expired", "del").
+	key := strings.TrimPrefix(msg.Channel, prefix)
+	event := Event{Key: key, Operation: msg.Payload, Timestamp: time.Now()}
+
+	w.mu.RLock()
+	subs := w.subscribers[key]
+	w.mu.RUnlock()
+
+	for _, sub := range subs {
+		select {
+		case sub <- event:
+		default:
+			log.Printf("⚠️ KeyWatcher: subscriber for %s is full, dropping event", key)
+		}
+	}
+}
+
+func nextBackoff(d time.Duration) time.Duration {
+	const max = 30 * time.Second
+	if d*2 > max {
+		return max
+	}
+	return d * 2
+}
@@ -0,0 +1,38 @@
+package config
+
+import "testing"
+
+func TestWatcherUnwatchRemovesSubscriber(t *testing.T) {
+	w := &Watcher{subscribers: make(map[string][]chan Event)}
+
+	ch := w.Watch("ball:u1")
+	if len(w.subscribers["ball:u1"]) != 1 {
+		t.Fatalf("expected 1 subscriber after Watch, got %d", len(w.subscribers["ball:u1"]))
+	}
+
+	w.Unwatch("ball:u1", ch)
+	if _, ok := w.subscribers["ball:u1"]; ok {
+		t.Fatalf("expected subscriber list to be removed after last Unwatch")
+	}
+
+	if _, open := <-ch; open {
+		t.Fatalf("expected channel to be closed by Unwatch")
+	}
+}
+
+func TestWatcherUnwatchKeepsOtherSubscribers(t *testing.T) {
+	w := &Watcher{subscribers: make(map[string][]chan Event)}
+
+	first := w.Watch("cup:1")
+	second := w.Watch("cup:1")
+
+	w.Unwatch("cup:1", first)
+
+	subs := w.subscribers["cup:1"]
+	if len(subs) != 1 {
+		t.Fatalf("expected 1 remaining subscriber, got %d", len(subs))
+	}
+	if subs[0] != second {
+		t.Fatalf("expected remaining subscriber to be the second channel")
+	}
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+
+	"github.com/permitio/permit-golang/pkg/enforcement"
+)
+
+// noCacheEnv disables the permission cache entirely, for debugging
+// permission decisions straight against Permit.io. The --no-cache CLI
+// flag (see main.go) takes precedence over it when set.
+const noCacheEnv = "PERM_NO_CACHE"
+
+// CacheMissCount counts permission checks that missed the Redis cache
+// and fell through to PermitClient. Exposed on /metrics.
+var CacheMissCount int64
+
+// noCacheFlag holds the --no-cache CLI flag's value, set by
+// SetNoCache. nil means the flag wasn't passed, so NoCache falls back
+// to the PERM_NO_CACHE env var.
+var noCacheFlag *bool
+
+// SetNoCache lets main.go's --no-cache flag override the PERM_NO_CACHE
+// env var once flags are parsed.
+func SetNoCache(v bool) {
+	noCacheFlag = &v
+}
+
+// NoCache reports whether the permission cache is disabled, via the
+// --no-cache flag or, failing that, the PERM_NO_CACHE env var.
+func NoCache() bool {
+	if noCacheFlag != nil {
+		return *noCacheFlag
+	}
+	return os.Getenv(noCacheEnv) != ""
+}
+
+// PermissionKey builds the Redis set key caching the actions a user is
+// allowed to perform on a resource.
+func PermissionKey(userID, resource string) string {
+	return fmt.Sprintf("perm:%s:%s", userID, resource)
+}
+
+// HydratePermissions seeds perm:<user>:<resource> sets in Redis, one
+// SADD per resource, replacing whatever was cached before. grants maps
+// a Redis key (built with PermissionKey) to the actions allowed on it.
+func HydratePermissions(ctx context.Context, grants map[string][]string) error {
+	if RedisClient == nil {
+		return fmt.Errorf("redis client is not initialized")
+	}
+
+	pipe := RedisClient.Pipeline()
+	for key, actions := range grants {
+		pipe.Del(ctx, key)
+		if len(actions) == 0 {
+			continue
+		}
+		members := make([]interface{}, len(actions))
+		for i, action := range actions {
+			members[i] = action
+		}
+		pipe.SAdd(ctx, key, members...)
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// CheckPermission checks perm:<user>:<resource> in Redis before falling
+// back to PermitClient.Check, so most requests avoid a call to
+// Permit.io entirely. Set PERM_NO_CACHE to always hit Permit.io.
+func CheckPermission(ctx context.Context, userID, resource, action string) (bool, error) {
+	if NoCache() || RedisClient == nil {
+		return checkPermit(userID, resource, action)
+	}
+
+	key := PermissionKey(userID, resource)
+	exists, err := RedisClient.Exists(ctx, key).Result()
+	if err != nil {
+		log.Printf("❌ Permission cache lookup failed for %s, falling back to Permit.io: %v", key, err)
+		return checkPermit(userID, resource, action)
+	}
+
+	if exists == 0 {
+		atomic.AddInt64(&CacheMissCount, 1)
+		return checkPermitAndCache(ctx, key, userID, resource, action)
+	}
+
+	member, err := RedisClient.SIsMember(ctx, key, action).Result()
+	if err != nil {
+		log.Printf("❌ Permission cache lookup failed for %s, falling back to Permit.io: %v", key, err)
+		return checkPermit(userID, resource, action)
+	}
+	if member {
+		return true, nil
+	}
+
+	// A cached key only ever holds the actions that were lazily SAdd'd
+	// on a past grant (see checkPermitAndCache), never a hydrated
+	// complete set unless HydratePermissions seeded it - so a negative
+	// SISMEMBER for an action that simply hasn't been checked yet
+	// would otherwise be mistaken for a denial. Fall back to Permit.io
+	// instead of trusting it, same as a full cache miss.
+	atomic.AddInt64(&CacheMissCount, 1)
+	return checkPermitAndCache(ctx, key, userID, resource, action)
+}
+
+// checkPermitAndCache calls Permit.io and, on a grant, SAdds action
+// into key so the next CheckPermission for the same action is a cache
+// hit. Denials are never cached: a denial cached here could outlive a
+// policy change that grants the action, with nothing to invalidate it
+// until the next webhook touches this exact key.
+func checkPermitAndCache(ctx context.Context, key, userID, resource, action string) (bool, error) {
+	allowed, err := checkPermit(userID, resource, action)
+	if err != nil {
+		return false, err
+	}
+
+	if allowed {
+		if err := RedisClient.SAdd(ctx, key, action).Err(); err != nil {
+			log.Printf("⚠️ Failed to cache permission %s for %s: %v", action, key, err)
+		}
+	}
+
+	return allowed, nil
+}
+
+func checkPermit(userID, resource, action string) (bool, error) {
+	if PermitClient == nil {
+		return false, fmt.Errorf("permit client is not initialized")
+	}
+
+	user := enforcement.UserBuilder(userID).WithRoles([]enforcement.AssignedRole{{Role: "user"}}).Build()
+	res := enforcement.ResourceBuilder(resource).Build()
+	return PermitClient.Check(user, enforcement.Action(action), res)
+}
+
+// policyInvalidations carries resources whose cached permissions need
+// to be dropped after a Permit.io policy update, delivered by the
+// webhook endpoint registered on api.Router.
+var policyInvalidations = make(chan PolicyUpdate, 64)
+
+// PolicyUpdate names a single perm:<user>:<resource> entry to evict.
+type PolicyUpdate struct {
+	UserID   string
+	Resource string
+}
+
+// InvalidatePermission queues a cache eviction for userID/resource. It
+// is called from the Permit.io webhook handler.
+func InvalidatePermission(userID, resource string) {
+	policyInvalidations <- PolicyUpdate{UserID: userID, Resource: resource}
+}
+
+// PermissionSync drains policyInvalidations until ctx is cancelled,
+// deleting the affected Redis key so the next CheckPermission call
+// falls through to Permit.io and repopulates it.
+func PermissionSync(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update := <-policyInvalidations:
+			key := PermissionKey(update.UserID, update.Resource)
+			if err := RedisClient.Del(ctx, key).Err(); err != nil {
+				log.Printf("❌ Failed to invalidate permission cache key %s: %v", key, err)
+				continue
+			}
+			log.Printf("✅ Invalidated permission cache for %s", key)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func withTestRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	prev := RedisClient
+	RedisClient = client
+	t.Cleanup(func() { RedisClient = prev })
+
+	return client
+}
+
+func TestNoCacheFlagTakesPrecedenceOverEnv(t *testing.T) {
+	t.Cleanup(func() { noCacheFlag = nil })
+
+	t.Setenv("PERM_NO_CACHE", "")
+	SetNoCache(true)
+	if !NoCache() {
+		t.Fatal("expected NoCache to be true once the flag is set, regardless of env")
+	}
+
+	t.Setenv("PERM_NO_CACHE", "1")
+	SetNoCache(false)
+	if NoCache() {
+		t.Fatal("expected NoCache to be false once the flag is explicitly cleared, regardless of env")
+	}
+
+	noCacheFlag = nil
+	if !NoCache() {
+		t.Fatal("expected NoCache to fall back to PERM_NO_CACHE env var once the flag is unset")
+	}
+}
+
+func TestHydratePermissionsSeedsCache(t *testing.T) {
+	ctx := context.Background()
+	client := withTestRedisClient(t)
+
+	key := PermissionKey("alice", "cup1")
+	if err := HydratePermissions(ctx, map[string][]string{key: {"beer", "pour"}}); err != nil {
+		t.Fatalf("HydratePermissions failed: %v", err)
+	}
+
+	ok, err := client.SIsMember(ctx, key, "beer").Result()
+	if err != nil {
+		t.Fatalf("SIsMember failed: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected %q to contain %q after hydration", key, "beer")
+	}
+}
+
+func TestCheckPermissionCacheHitSkipsPermit(t *testing.T) {
+	ctx := context.Background()
+	client := withTestRedisClient(t)
+	t.Cleanup(func() { noCacheFlag = nil })
+	SetNoCache(false)
+
+	key := PermissionKey("alice", "cup1")
+	if err := client.SAdd(ctx, key, "beer").Err(); err != nil {
+		t.Fatalf("SAdd failed: %v", err)
+	}
+
+	// PermitClient is deliberately left nil: a cache hit must never
+	// fall through to checkPermit, so this would panic/error if it did.
+	allowed, err := CheckPermission(ctx, "alice", "cup1", "beer")
+	if err != nil {
+		t.Fatalf("CheckPermission failed: %v", err)
+	}
+	if !allowed {
+		t.Fatal("expected cached grant to report allowed=true")
+	}
+}
+
+func TestCheckPermissionFallsBackToPermitOnNegativeMembership(t *testing.T) {
+	ctx := context.Background()
+	client := withTestRedisClient(t)
+	t.Cleanup(func() { noCacheFlag = nil })
+	SetNoCache(false)
+
+	// The key exists (so Exists > 0) but only caches a different
+	// action, as lazy single-action caching would leave it. Checking
+	// an uncached action must still consult Permit.io rather than
+	// treating the negative SISMEMBER as a denial.
+	key := PermissionKey("alice", "cup1")
+	if err := client.SAdd(ctx, key, "beer").Err(); err != nil {
+		t.Fatalf("SAdd failed: %v", err)
+	}
+
+	// PermitClient is deliberately left nil: CheckPermission returning
+	// its "permit client is not initialized" error (rather than a
+	// silent allowed=false) proves it fell through to checkPermit
+	// instead of trusting the negative cache membership.
+	_, err := CheckPermission(ctx, "alice", "cup1", "pour")
+	if err == nil {
+		t.Fatal("expected CheckPermission to fall back to Permit.io for an uncached action and surface its error")
+	}
+}
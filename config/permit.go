@@ -1,16 +1,16 @@
 package config
 
 import (
-	"github.com/permitio/permit-golang/pkg/config"
+	permitconfig "github.com/permitio/permit-golang/pkg/config"
 	"github.com/permitio/permit-golang/pkg/permit"
 )
 
+// PermitClient is the package-level client set up from PermitInit's
+// result, mirroring RedisClient.
 var PermitClient *permit.Client
 
-func PermitInit() {
-
-	apiKey := CheckEnv("API_KEY")
-
-	permitConfig := config.NewConfigBuilder(apiKey).WithPdpUrl("https://cloudpdp.api.permit.io").Build()
-	PermitClient = permit.New(permitConfig)
+// PermitInit builds a Permit.io client from cfg.
+func PermitInit(cfg PermitConfig) *permit.Client {
+	permitConfig := permitconfig.NewConfigBuilder(cfg.APIKey).WithPdpUrl(cfg.PDPUrl).Build()
+	return permit.New(permitConfig)
 }
@@ -2,31 +2,35 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"log"
-	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// RedisClient is the package-level client set up from RedisInit's
+// result, so the rest of the package (KeyWatcher, permission cache)
+// can keep using it without threading a client through every call.
 var RedisClient *redis.Client
 
-// Initialize redis
-func RedisInit() {
-	RedisClient = redis.NewClient(&redis.Options{
-		Addr:        "127.0.0.1:6379",
-		Password:    "",
-		DB:          0,
-		DialTimeout: 20 * time.Second,
+// RedisInit builds a Redis client from cfg and pings it. It returns
+// the client rather than setting a global directly, so callers (and
+// tests) can build multiple independently-configured clients.
+func RedisInit(cfg RedisConfig) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:        cfg.Addr,
+		Password:    cfg.Password,
+		DB:          cfg.DB,
+		DialTimeout: cfg.DialTimeout,
 	})
 
-	// Test the Redis connection
-	ctx := context.Background()
-	_, err := RedisClient.Ping(ctx).Result()
-	if err != nil {
-		log.Printf("❌ Failed to connect to Redis: %v", err)
-		RedisClient = nil
-		return
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
+	defer cancel()
+
+	if _, err := client.Ping(ctx).Result(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", cfg.Addr, err)
 	}
 
 	log.Println("✅ Redis Successfully connected!")
+	return client, nil
 }
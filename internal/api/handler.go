@@ -1,7 +1,6 @@
 package api
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,8 +9,10 @@ import (
 	"time"
 
 	"github.com/30Piraten/beerPong/config"
-	"github.com/gorilla/mux"
-	"github.com/permitio/permit-golang/pkg/enforcement"
+	"github.com/30Piraten/beerPong/internal/api/ws"
+	"github.com/30Piraten/beerPong/internal/events"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 type BallRequest struct {
@@ -19,6 +20,7 @@ type BallRequest struct {
 	Role   string `json:"role"`
 	Action string `json:"action"`
 	Target string `json:"target"`
+	GameID string `json:"game_id"`
 }
 
 // throwBallHandler handles the initial req -> ball thrown
@@ -52,20 +54,38 @@ func throwBallHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("Checking permission for user: %s, action: %s:, target: %s", req.UserID, req.Action, req.Target)
 
-	ctx := context.Background()
+	ctx := r.Context()
 	// Save the req state in redis
 	redisKey := fmt.Sprintf("ball:%s", req.UserID)
 
-	if err := config.RedisClient.Set(ctx, redisKey, req.Target, time.Minute*5).Err(); err != nil {
+	event := events.BallThrownEvent{
+		UserID:    req.UserID,
+		Target:    req.Target,
+		Action:    req.Action,
+		Timestamp: time.Now(),
+		TraceID:   uuid.NewString(),
+	}
+
+	// Write the Redis state and the Kafka outbox entry atomically, so a
+	// Kafka outage can't lose the throw or duplicate this 500.
+	if err := events.Enqueue(ctx, config.RedisClient, redisKey, req.Target, event); err != nil {
 		log.Printf("❌ Redis error: %v", err)
-		log.Printf("Redis SET: ball:%s ex 300", req.Target) // Added here!
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
-	log.Printf("✅ Redis SET: %s -> %s (TTL: 5m)", redisKey, req.Target)
+	log.Printf("✅ Redis SET: %s -> %s (TTL: 5m), queued for Kafka (trace: %s)", redisKey, req.Target, event.TraceID)
 
-	// Now forward to Kafka (Placeholder)
-	log.Printf("Ball thrown by %s for %s -> Passing to Kafka\n", req.UserID, req.Target)
+	if req.GameID != "" {
+		payload, _ := json.Marshal(map[string]string{
+			"user_id": req.UserID,
+			"target":  req.Target,
+			"action":  req.Action,
+		})
+		wsEvent := ws.Event{Type: ws.BallThrown, Data: payload}
+		if err := ws.PublishGameEvent(ctx, config.RedisClient, req.GameID, wsEvent); err != nil {
+			log.Printf("❌ Failed to publish ball_thrown event for game %s: %v", req.GameID, err)
+		}
+	}
 
 	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(map[string]string{
@@ -79,22 +99,20 @@ func cupHandler(w http.ResponseWriter, r *http.Request) {
 	if config.PermitClient == nil {
 		log.Println("❌ Permit.io is not initialized")
 		http.Error(w, "Permit.io failed initialization", http.StatusInternalServerError)
+		return
 	}
 
-	vars := mux.Vars(r)
-	cupID := vars["cup_id"]
-
-	var req BallRequest
-
-	// Check the user permission for this cup
-	userID := enforcement.UserBuilder(req.UserID).WithRoles([]enforcement.AssignedRole{{Role: "user"}}).Build()
-	resource := enforcement.ResourceBuilder(cupID).Build()
-	action := enforcement.Action("beer") // used for log
-
-	_, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	cupID := chi.URLParam(r, "cup_id")
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		http.Error(w, "Missing user_id query parameter", http.StatusBadRequest)
+		return
+	}
 
-	allowed, err := config.PermitClient.Check(userID, "beer", resource)
+	// Cache-first permission check: SISMEMBER perm:<user>:<cup_id> beer
+	// before falling back to Permit.io. Per-route timeout is applied by
+	// the router's middleware.Timeout, not here.
+	allowed, err := config.CheckPermission(r.Context(), userID, cupID, "beer")
 
 	if err != nil {
 		http.Error(w, "Permission check failed", http.StatusInternalServerError)
@@ -110,6 +128,22 @@ func cupHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Access denied!", http.StatusForbidden)
 	}
 
-	log.Printf("Checking permission for permit.check: %s, action: %s", userID, action)
+	log.Printf("Checking permission for user: %s, cup: %s, action: beer", userID, cupID)
+}
+
+// permitWebhookHandler receives Permit.io policy-update webhooks and
+// invalidates the affected user/resource entry in the permission cache.
+func permitWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		UserID   string `json:"user_id"`
+		Resource string `json:"resource"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid webhook payload", http.StatusBadRequest)
+		return
+	}
 
+	config.InvalidatePermission(payload.UserID, payload.Resource)
+	w.WriteHeader(http.StatusNoContent)
 }
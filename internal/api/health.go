@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/30Piraten/beerPong/config"
+	"github.com/permitio/permit-golang/pkg/enforcement"
+)
+
+// healthzHandler reports whether the process itself is up, with no
+// dependency checks.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// readyzHandler reports whether Redis and Permit.io are actually
+// reachable, so a load balancer can hold traffic back until both are.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if config.RedisClient == nil {
+		http.Error(w, "redis not ready", http.StatusServiceUnavailable)
+		return
+	}
+	if _, err := config.RedisClient.Ping(ctx).Result(); err != nil {
+		http.Error(w, "redis not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	if config.PermitClient == nil {
+		http.Error(w, "permit.io not ready", http.StatusServiceUnavailable)
+		return
+	}
+	// The SDK has no dedicated health call; a throwaway Check doubles
+	// as a liveness probe for the PDP connection.
+	user := enforcement.UserBuilder("healthcheck").Build()
+	resource := enforcement.ResourceBuilder("healthcheck").Build()
+	if _, err := config.PermitClient.Check(user, enforcement.Action("beer"), resource); err != nil {
+		http.Error(w, "permit.io not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ready"))
+}
+
+// metricsHandler reports lightweight counters that don't warrant a
+// full Prometheus setup yet, starting with the permission cache's
+// miss count.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{
+		"permission_cache_miss_count": atomic.LoadInt64(&config.CacheMissCount),
+	})
+}
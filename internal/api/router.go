@@ -1,12 +1,40 @@
 package api
 
-import "github.com/gorilla/mux"
+import (
+	"time"
 
-func Router() *mux.Router {
+	"github.com/30Piraten/beerPong/internal/api/ws"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
 
-	r := mux.NewRouter()
-	r.HandleFunc("/throw", throwBallHandler).Methods("POST")
-	r.HandleFunc("/cup/{cup_id}", cupHandler).Methods("GET")
+// routeTimeout bounds how long a single request is allowed to take
+// before its context is cancelled, replacing the dead, discarded
+// context.WithTimeout that used to live in cupHandler.
+const routeTimeout = 10 * time.Second
+
+func Router(logLevel string, wsAllowedOrigins []string) *chi.Mux {
+	logger := newLogger(logLevel)
+	ws.SetAllowedOrigins(wsAllowedOrigins)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(accessLog(logger))
+	r.Use(recoverer(logger))
+
+	r.Get("/healthz", healthzHandler)
+	r.Get("/readyz", readyzHandler)
+	r.Get("/metrics", metricsHandler)
+	// The WebSocket session lives far longer than routeTimeout, so it
+	// is deliberately kept out of the timeout group below.
+	r.Get("/session/{game_id}/ws", sessionWSHandler)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(routeTimeout))
+		r.Post("/throw", throwBallHandler)
+		r.Get("/cup/{cup_id}", cupHandler)
+		r.Post("/webhooks/permit", permitWebhookHandler)
+	})
 
 	return r
 }
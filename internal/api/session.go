@@ -0,0 +1,17 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/30Piraten/beerPong/config"
+	"github.com/30Piraten/beerPong/internal/api/ws"
+	"github.com/go-chi/chi/v5"
+)
+
+// sessionWSHandler upgrades to a WebSocket that streams ball_thrown,
+// cup_hit, permission_denied and game_over events for game_id.
+func sessionWSHandler(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "game_id")
+	hub := ws.GetHub(config.RedisClient, gameID)
+	ws.ServeWS(hub, w, r)
+}
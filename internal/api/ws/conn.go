@@ -0,0 +1,127 @@
+package ws
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	maxMessageSize = 4096
+)
+
+// allowedOrigins is set once at startup by SetAllowedOrigins. An empty
+// slice allows every origin.
+var allowedOrigins []string
+
+// SetAllowedOrigins configures which Origin headers the WebSocket
+// upgrader accepts. Called once from router setup with config.WS.
+func SetAllowedOrigins(origins []string) {
+	allowedOrigins = origins
+}
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: checkOrigin,
+}
+
+func checkOrigin(r *http.Request) bool {
+	if len(allowedOrigins) == 0 {
+		return true
+	}
+
+	origin := r.Header.Get("Origin")
+	for _, allowed := range allowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeWS upgrades r to a WebSocket connection and registers it with
+// hub, starting its read and write pumps.
+func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("❌ WebSocket upgrade failed: %v", err)
+		return
+	}
+
+	c := &client{hub: hub, conn: conn, send: make(chan []byte, 16)}
+
+	// hub may already be tearing down (GetHub can return an entry
+	// racing with teardownOnEmpty/removeHub between h.empty firing and
+	// the map delete) - an unguarded send here would block forever
+	// once Run has returned.
+	select {
+	case hub.register <- c:
+	case <-hub.done:
+		conn.Close()
+		return
+	}
+
+	go c.writePump()
+	go c.readPump()
+}
+
+// readPump keeps the connection's read deadline alive via pongs and
+// discards any messages the client sends; this endpoint is
+// broadcast-only. It unregisters the client once the connection
+// drops.
+func (c *client) readPump() {
+	defer func() {
+		select {
+		case c.hub.unregister <- c:
+		case <-c.hub.done:
+		}
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+}
+
+// writePump delivers broadcast messages to the connection and sends
+// periodic pings to keep it alive.
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
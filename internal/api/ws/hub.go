@@ -0,0 +1,139 @@
+// Package ws broadcasts live game events (throws, hits, permission
+// denials, game over) to every WebSocket connection watching a game.
+package ws
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// EventType names the kind of game event sent over a session's
+// WebSocket connections.
+type EventType string
+
+const (
+	BallThrown       EventType = "ball_thrown"
+	CupHit           EventType = "cup_hit"
+	PermissionDenied EventType = "permission_denied"
+	GameOver         EventType = "game_over"
+)
+
+// Event is the JSON envelope broadcast to every client of a game.
+type Event struct {
+	Type EventType       `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// client is a single WebSocket connection registered with a Hub.
+type client struct {
+	hub  *Hub
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// Hub fans broadcast messages out to every registered client of one
+// game session.
+type Hub struct {
+	mu         sync.Mutex
+	clients    map[*client]bool
+	register   chan *client
+	unregister chan *client
+	broadcast  chan []byte
+
+	// empty fires (best-effort, non-blocking) whenever the last client
+	// unregisters, so GetHub can tear the session down instead of
+	// leaking it forever.
+	empty chan struct{}
+	// done is closed by Stop to end Run and release every remaining
+	// client; readPump/writePump watch it to avoid blocking on a Hub
+	// that has already shut down.
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewHub builds an empty Hub. Call Run in its own goroutine to start
+// it.
+func NewHub() *Hub {
+	return &Hub{
+		clients:    make(map[*client]bool),
+		register:   make(chan *client),
+		unregister: make(chan *client),
+		broadcast:  make(chan []byte, 256),
+		empty:      make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+}
+
+// Run processes register/unregister/broadcast until Stop is called; it
+// is meant to run for the lifetime of the Hub in its own goroutine.
+func (h *Hub) Run() {
+	for {
+		select {
+		case c := <-h.register:
+			h.mu.Lock()
+			h.clients[c] = true
+			h.mu.Unlock()
+
+		case c := <-h.unregister:
+			h.mu.Lock()
+			if _, ok := h.clients[c]; ok {
+				delete(h.clients, c)
+				close(c.send)
+			}
+			empty := len(h.clients) == 0
+			h.mu.Unlock()
+
+			if empty {
+				select {
+				case h.empty <- struct{}{}:
+				default:
+				}
+			}
+
+		case msg := <-h.broadcast:
+			h.mu.Lock()
+			for c := range h.clients {
+				select {
+				case c.send <- msg:
+				default:
+					// Client is too slow to keep up; drop it rather
+					// than block the whole game's broadcasts.
+					delete(h.clients, c)
+					close(c.send)
+				}
+			}
+			h.mu.Unlock()
+
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// Stop ends Run and releases every still-registered client. It is
+// idempotent, so both the empty-hub and game-over teardown paths can
+// call it safely.
+func (h *Hub) Stop() {
+	h.stopOnce.Do(func() {
+		close(h.done)
+
+		h.mu.Lock()
+		for c := range h.clients {
+			close(c.send)
+			delete(h.clients, c)
+		}
+		h.mu.Unlock()
+	})
+}
+
+// Broadcast marshals event and queues it for every registered client.
+func (h *Hub) Broadcast(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	h.broadcast <- payload
+	return nil
+}
@@ -0,0 +1,155 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// hubEntry pairs a game's Hub with the cancel func for its Redis
+// bridge subscription, so removeHub can tear both down together.
+type hubEntry struct {
+	hub    *Hub
+	cancel context.CancelFunc
+}
+
+var (
+	hubsMu sync.Mutex
+	hubs   = make(map[string]*hubEntry)
+)
+
+// GetHub returns the Hub for gameID, creating it (and a Redis
+// subscriber bridging game:<gameID>:events into it) on first use. The
+// hub is torn down automatically once its last client disconnects or
+// a game_over event comes through, so long-lived games don't leak
+// goroutines or subscriptions.
+func GetHub(rdb *redis.Client, gameID string) *Hub {
+	hubsMu.Lock()
+	defer hubsMu.Unlock()
+
+	if e, ok := hubs[gameID]; ok {
+		select {
+		case <-e.hub.done:
+			// Stopped but not yet removed from the map - teardownOnEmpty
+			// and this lookup raced. Treat it as gone rather than
+			// handing back a hub nothing is running Run() for anymore;
+			// ServeWS also guards its register send for the remaining
+			// window between this check and the send.
+			delete(hubs, gameID)
+			e.cancel()
+		default:
+			return e.hub
+		}
+	}
+
+	h := NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	hubs[gameID] = &hubEntry{hub: h, cancel: cancel}
+
+	go h.Run()
+	if rdb != nil {
+		go bridgeRedis(ctx, rdb, gameID, h)
+	}
+	go teardownOnEmpty(gameID, h)
+
+	return h
+}
+
+// teardownOnEmpty removes gameID's hub once its last client
+// disconnects, or once the hub is stopped some other way (e.g. a
+// game_over event in bridgeRedis), whichever happens first. If GetHub
+// has already replaced the entry with a newer hub, only h is stopped
+// and the newer hub is left alone.
+func teardownOnEmpty(gameID string, h *Hub) {
+	select {
+	case <-h.empty:
+	case <-h.done:
+	}
+
+	hubsMu.Lock()
+	e, ok := hubs[gameID]
+	ok = ok && e.hub == h
+	if ok {
+		delete(hubs, gameID)
+	}
+	hubsMu.Unlock()
+
+	if ok {
+		e.cancel()
+	}
+	h.Stop()
+}
+
+// removeHub deletes gameID's hub entry, cancels its Redis bridge
+// subscription and stops the hub. It is safe to call more than once.
+func removeHub(gameID string) {
+	hubsMu.Lock()
+	e, ok := hubs[gameID]
+	if ok {
+		delete(hubs, gameID)
+	}
+	hubsMu.Unlock()
+
+	if !ok {
+		return
+	}
+	e.cancel()
+	e.hub.Stop()
+}
+
+// channelFor is the Redis pub/sub channel a game's events are
+// published and subscribed on.
+func channelFor(gameID string) string {
+	return fmt.Sprintf("game:%s:events", gameID)
+}
+
+// PublishGameEvent publishes event on gameID's channel, so every
+// subscribing Hub (including ones running in other instances of this
+// service) forwards it to its WebSocket clients.
+func PublishGameEvent(ctx context.Context, rdb *redis.Client, gameID string, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return rdb.Publish(ctx, channelFor(gameID), payload).Err()
+}
+
+// bridgeRedis subscribes to gameID's channel and forwards each message
+// to hub's clients until ctx is cancelled (by removeHub) or the
+// subscription is closed; a message that arrives while the hub's
+// broadcast buffer is full is dropped. A game_over event tears the hub
+// down once it's been forwarded.
+func bridgeRedis(ctx context.Context, rdb *redis.Client, gameID string, hub *Hub) {
+	pubsub := rdb.Subscribe(ctx, channelFor(gameID))
+	defer pubsub.Close()
+
+	msgs := pubsub.Channel()
+	for {
+		var msg *redis.Message
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-msgs:
+			if !ok {
+				return
+			}
+			msg = m
+		}
+
+		select {
+		case hub.broadcast <- []byte(msg.Payload):
+		default:
+			log.Printf("⚠️ Hub for game %s is full, dropping event", gameID)
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err == nil && event.Type == GameOver {
+			removeHub(gameID)
+			return
+		}
+	}
+}
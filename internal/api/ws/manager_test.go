@@ -0,0 +1,37 @@
+package ws
+
+import (
+	"testing"
+)
+
+func TestGetHubReturnsSameHubOnRepeatedCalls(t *testing.T) {
+	t.Cleanup(func() { removeHub("game-1") })
+
+	h1 := GetHub(nil, "game-1")
+	h2 := GetHub(nil, "game-1")
+
+	if h1 != h2 {
+		t.Fatal("expected GetHub to return the same Hub for the same gameID")
+	}
+}
+
+func TestGetHubReplacesAlreadyStoppedEntry(t *testing.T) {
+	t.Cleanup(func() { removeHub("game-2") })
+
+	h1 := GetHub(nil, "game-2")
+
+	// Simulate the race the review flagged: the hub is stopped (e.g.
+	// by teardownOnEmpty) before the map entry is removed.
+	h1.Stop()
+
+	h2 := GetHub(nil, "game-2")
+	if h1 == h2 {
+		t.Fatal("expected GetHub to create a fresh Hub once the found entry was already stopped")
+	}
+
+	select {
+	case <-h2.done:
+		t.Fatal("expected the freshly created Hub to not be stopped")
+	default:
+	}
+}
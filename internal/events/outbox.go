@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamKey is the Redis stream used as a transactional outbox for ball
+// events: the HTTP handler only ever has to talk to Redis, and the
+// OutboxWorker is responsible for getting events to Kafka, so a Kafka
+// outage never turns into a lost throw or a duplicate 500.
+const StreamKey = "ball:events"
+
+// Enqueue writes the ball's Redis state and the outbox entry for event
+// in a single MULTI/EXEC pipeline, so either both happen or neither does.
+func Enqueue(ctx context.Context, rdb *redis.Client, ballKey, target string, event BallThrownEvent) error {
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, ballKey, target, time.Minute*5)
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: StreamKey,
+			Values: map[string]interface{}{
+				"user_id":   event.UserID,
+				"target":    event.Target,
+				"action":    event.Action,
+				"timestamp": event.Timestamp.Format(time.RFC3339Nano),
+				"trace_id":  event.TraceID,
+			},
+		})
+		return nil
+	})
+	return err
+}
+
+// OutboxWorker drains StreamKey to a Kafka Producer. Well-formed entries
+// are only deleted from the stream once Kafka has acked them, so a
+// publish failure just leaves the entry to be retried on the next tick;
+// entries that cannot be parsed are logged and deleted without being
+// published.
+type OutboxWorker struct {
+	redis    *redis.Client
+	producer Producer
+	interval time.Duration
+}
+
+// NewOutboxWorker builds an OutboxWorker that polls StreamKey every
+// interval.
+func NewOutboxWorker(rdb *redis.Client, producer Producer, interval time.Duration) *OutboxWorker {
+	return &OutboxWorker{
+		redis:    rdb,
+		producer: producer,
+		interval: interval,
+	}
+}
+
+// Run polls StreamKey until ctx is cancelled.
+func (w *OutboxWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.drain(ctx)
+		}
+	}
+}
+
+func (w *OutboxWorker) drain(ctx context.Context) {
+	if w.producer == nil {
+		log.Printf("❌ No Kafka producer configured, leaving outbox stream %s undrained", StreamKey)
+		return
+	}
+
+	entries, err := w.redis.XRange(ctx, StreamKey, "-", "+").Result()
+	if err != nil {
+		log.Printf("❌ Failed to read outbox stream %s: %v", StreamKey, err)
+		return
+	}
+
+	for _, entry := range entries {
+		event, err := parseEntry(entry.Values)
+		if err != nil {
+			log.Printf("❌ Dropping malformed outbox entry %s: %v", entry.ID, err)
+			w.redis.XDel(ctx, StreamKey, entry.ID)
+			continue
+		}
+
+		if err := w.producer.Publish(ctx, event); err != nil {
+			log.Printf("❌ Kafka publish failed, leaving %s in outbox for retry: %v", entry.ID, err)
+			return
+		}
+
+		if err := w.redis.XDel(ctx, StreamKey, entry.ID).Err(); err != nil {
+			log.Printf("❌ Failed to remove acked outbox entry %s: %v", entry.ID, err)
+		}
+	}
+}
+
+func parseEntry(values map[string]interface{}) (BallThrownEvent, error) {
+	userID, _ := values["user_id"].(string)
+	target, _ := values["target"].(string)
+	action, _ := values["action"].(string)
+	traceID, _ := values["trace_id"].(string)
+
+	ts, _ := values["timestamp"].(string)
+	timestamp, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return BallThrownEvent{}, fmt.Errorf("invalid timestamp %q: %w", ts, err)
+	}
+
+	return BallThrownEvent{
+		UserID:    userID,
+		Target:    target,
+		Action:    action,
+		Timestamp: timestamp,
+		TraceID:   traceID,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type fakeProducer struct {
+	mu        sync.Mutex
+	published []BallThrownEvent
+	failNext  bool
+}
+
+func (f *fakeProducer) Publish(ctx context.Context, event BallThrownEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failNext {
+		f.failNext = false
+		return errors.New("kafka unavailable")
+	}
+	f.published = append(f.published, event)
+	return nil
+}
+
+func (f *fakeProducer) Close() error { return nil }
+
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+	return redis.NewClient(&redis.Options{Addr: mr.Addr()})
+}
+
+func TestOutboxWorkerDrainPublishesAndDeletesEntries(t *testing.T) {
+	ctx := context.Background()
+	rdb := newTestRedis(t)
+	producer := &fakeProducer{}
+
+	event := BallThrownEvent{UserID: "u1", Target: "cup-3", Action: "throw", Timestamp: time.Now(), TraceID: "trace-1"}
+	if err := Enqueue(ctx, rdb, "ball:u1", event.Target, event); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	w := NewOutboxWorker(rdb, producer, time.Second)
+	w.drain(ctx)
+
+	producer.mu.Lock()
+	got := len(producer.published)
+	producer.mu.Unlock()
+	if got != 1 {
+		t.Fatalf("expected 1 published event, got %d", got)
+	}
+
+	entries, err := rdb.XRange(ctx, StreamKey, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("XRange failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected outbox stream to be drained, got %d entries left", len(entries))
+	}
+}
+
+func TestOutboxWorkerDrainRetriesOnPublishFailure(t *testing.T) {
+	ctx := context.Background()
+	rdb := newTestRedis(t)
+	producer := &fakeProducer{failNext: true}
+
+	event := BallThrownEvent{UserID: "u2", Target: "cup-1", Action: "throw", Timestamp: time.Now(), TraceID: "trace-2"}
+	if err := Enqueue(ctx, rdb, "ball:u2", event.Target, event); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	w := NewOutboxWorker(rdb, producer, time.Second)
+	w.drain(ctx)
+
+	entries, err := rdb.XRange(ctx, StreamKey, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("XRange failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected failed entry to remain in outbox, got %d entries", len(entries))
+	}
+
+	w.drain(ctx)
+
+	entries, err = rdb.XRange(ctx, StreamKey, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("XRange failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected entry to drain on retry, got %d entries left", len(entries))
+	}
+}
+
+func TestOutboxWorkerDrainNilProducerDoesNotPanic(t *testing.T) {
+	ctx := context.Background()
+	rdb := newTestRedis(t)
+
+	event := BallThrownEvent{UserID: "u3", Target: "cup-2", Action: "throw", Timestamp: time.Now(), TraceID: "trace-3"}
+	if err := Enqueue(ctx, rdb, "ball:u3", event.Target, event); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	w := NewOutboxWorker(rdb, nil, time.Second)
+	w.drain(ctx)
+
+	entries, err := rdb.XRange(ctx, StreamKey, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("XRange failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected entry to remain undrained with nil producer, got %d entries", len(entries))
+	}
+}
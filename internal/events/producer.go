@@ -0,0 +1,80 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/30Piraten/beerPong/config"
+	"github.com/segmentio/kafka-go"
+)
+
+// BallThrownEvent is published every time a ball is thrown, so downstream
+// consumers (scoring, analytics, the websocket hub) can react without
+// coupling to the HTTP handler.
+type BallThrownEvent struct {
+	UserID    string    `json:"user_id"`
+	Target    string    `json:"target"`
+	Action    string    `json:"action"`
+	Timestamp time.Time `json:"timestamp"`
+	TraceID   string    `json:"trace_id"`
+}
+
+// Producer publishes ball events to the event bus. It is an interface so
+// the outbox worker can be tested against a fake without a live broker.
+type Producer interface {
+	Publish(ctx context.Context, event BallThrownEvent) error
+	Close() error
+}
+
+type kafkaProducer struct {
+	writer *kafka.Writer
+}
+
+// NewProducer builds a Kafka-backed Producer with synchronous acks from
+// every in-sync replica, so a publish error always means the event was
+// NOT durably delivered and it is safe to retry from the outbox.
+func NewProducer(brokers []string, topic string) Producer {
+	return &kafkaProducer{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.LeastBytes{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
+
+func (p *kafkaProducer) Publish(ctx context.Context, event BallThrownEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ball event: %w", err)
+	}
+
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(event.UserID),
+		Value: payload,
+	})
+}
+
+func (p *kafkaProducer) Close() error {
+	return p.writer.Close()
+}
+
+// KafkaProducer is the package-level Producer, initialized by Init
+// alongside config.RedisClient and config.PermitClient.
+var KafkaProducer Producer
+
+// Init sets up KafkaProducer from cfg. It is called from main.go after
+// RedisInit and PermitInit.
+func Init(cfg config.KafkaConfig) {
+	if len(cfg.Brokers) == 0 || cfg.Topic == "" {
+		log.Println("❌ Kafka config is incomplete, producer not initialized")
+		return
+	}
+
+	KafkaProducer = NewProducer(cfg.Brokers, cfg.Topic)
+	log.Printf("✅ Kafka producer initialized (brokers: %v, topic: %s)", cfg.Brokers, cfg.Topic)
+}
@@ -1,30 +1,81 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/30Piraten/beerPong/config"
 	router "github.com/30Piraten/beerPong/internal/api"
+	"github.com/30Piraten/beerPong/internal/events"
 	"github.com/joho/godotenv"
 )
 
 func main() {
 	fmt.Println("Beer Pong Permissions Game")
 
-	// Load .env
+	// Load .env, if present; real deployments may inject env vars
+	// directly instead.
 	if err := godotenv.Load(); err != nil {
-		log.Fatalf("Failed to load .env variables: %v", err)
+		log.Printf("⚠️ No .env file loaded: %v", err)
+	}
+
+	configPath := flag.String("config", "./beerpong.toml", "path to the beerpong config file")
+	noCache := flag.Bool("no-cache", false, "disable the permission cache and always hit Permit.io (env PERM_NO_CACHE also works)")
+	flag.Parse()
+
+	// Only an explicitly passed --no-cache (true or false) overrides
+	// PERM_NO_CACHE.
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "no-cache" {
+			config.SetNoCache(*noCache)
+		}
+	})
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	// Initialize Redis & Permit.io
-	config.RedisInit()
-	config.PermitInit()
+	redisClient, err := config.RedisInit(cfg.Redis)
+	if err != nil {
+		log.Printf("❌ %v", err)
+	}
+	config.RedisClient = redisClient
+	config.PermitClient = config.PermitInit(cfg.Permit)
+
+	if config.RedisClient != nil && len(cfg.Permissions) > 0 {
+		if err := config.HydratePermissions(context.Background(), cfg.Permissions); err != nil {
+			log.Printf("❌ Failed to hydrate permission cache: %v", err)
+		} else {
+			log.Printf("✅ Permission cache hydrated with %d grants", len(cfg.Permissions))
+		}
+	}
+
+	config.KeyWatcherInit()
+	events.Init(cfg.Kafka)
+
+	if config.RedisClient != nil {
+		if events.KafkaProducer != nil {
+			// Drain the Redis outbox to Kafka in the background.
+			outbox := events.NewOutboxWorker(config.RedisClient, events.KafkaProducer, 2*time.Second)
+			go outbox.Run(context.Background())
+		} else {
+			log.Println("⚠️ Kafka producer not configured, outbox will not be drained")
+		}
+
+		// Invalidate cached permissions when Permit.io webhooks report a
+		// policy change.
+		go config.PermissionSync(context.Background())
+	}
 
-	r := router.Router()
-	fmt.Println("Server trekking at :1224")
-	if err := http.ListenAndServe(":1224", r); err != nil {
+	r := router.Router(cfg.Log.Level, cfg.WS.AllowedOrigins)
+	fmt.Printf("Server trekking at %s\n", cfg.Listen)
+	if err := http.ListenAndServe(cfg.Listen, r); err != nil {
 		log.Fatalf("Server failed to start trekking: %v", err)
 	}
 }